@@ -6,7 +6,6 @@ package sdkv1_test
 import (
 	"context"
 	"net/http/httptrace"
-	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -15,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 
 	helper "github.com/scylladb/alternator-client-golang/sdkv1"
+	"github.com/scylladb/alternator-client-golang/shared/metrics"
 )
 
 // TestHTTPConnectionReuse verifies that the client reuses HTTP connections
@@ -74,34 +74,23 @@ func testConnectionReuse(t *testing.T, scheme string, port int, ignoreCertErrors
 
 	var newConnCount atomic.Int32
 	var reusedConnCount atomic.Int32
-	var mu sync.Mutex
-	seenConns := make(map[string]bool)
 
-	numRequests := 20
-	for i := 0; i < numRequests; i++ {
-		trace := &httptrace.ClientTrace{
-			GotConn: func(info httptrace.GotConnInfo) {
-				mu.Lock()
-				defer mu.Unlock()
-
-				connKey := info.Conn.LocalAddr().String() + "->" + info.Conn.RemoteAddr().String()
-
-				if info.Reused {
-					reusedConnCount.Add(1)
-					t.Logf("Request %d: Connection REUSED: %s", i, connKey)
-				} else {
-					if seenConns[connKey] {
-						t.Logf("Request %d: RECONNECTION detected: %s", i, connKey)
-					} else {
-						newConnCount.Add(1)
-						seenConns[connKey] = true
-						t.Logf("Request %d: NEW connection: %s", i, connKey)
-					}
-				}
-			},
+	// ConnReuseTracer distinguishes a genuinely new TCP connection from a
+	// request multiplexed onto one that's already open; reading
+	// httptrace.GotConnInfo.Reused directly is unreliable for that under
+	// HTTP/2, since concurrent requests racing to multiplex a stream onto
+	// the same connection can each observe Reused=false.
+	tracer := metrics.NewConnReuseTracer(func(info httptrace.GotConnInfo, streamReused bool) {
+		if streamReused || info.Reused {
+			reusedConnCount.Add(1)
+			return
 		}
+		newConnCount.Add(1)
+	})
 
-		traceCtx := httptrace.WithClientTrace(ctx, trace)
+	numRequests := 20
+	for i := 0; i < numRequests; i++ {
+		traceCtx := httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{GotConn: tracer.GotConn})
 
 		_, err := ddb.GetItemWithContext(traceCtx, &dynamodb.GetItemInput{
 			TableName: aws.String(tableName),
@@ -119,18 +108,23 @@ func testConnectionReuse(t *testing.T, scheme string, port int, ignoreCertErrors
 	newConns := newConnCount.Load()
 	reusedConns := reusedConnCount.Load()
 
-	expectedMaxNewConns := int32(8)
+	// Each node gets its own per-host idle pool, so the number of new
+	// connections should scale with the node count rather than stay below
+	// a single global constant: one (or a couple, for load-balancing
+	// slack) new connection per node, not per request.
+	nodeCount := int32(len(knownNodes))
+	expectedMaxNewConns := 2 * nodeCount
 
 	if newConns > expectedMaxNewConns {
-		t.Errorf("Too many new connections created: %d (expected ≤ %d). "+
-			"Check MaxIdleConnsPerHost setting.",
-			newConns, expectedMaxNewConns)
+		t.Errorf("Too many new connections created: %d (expected ≤ %d for %d nodes). "+
+			"Check MaxIdleHTTPConnectionsPerHost setting.",
+			newConns, expectedMaxNewConns, nodeCount)
 	}
 
-	minReusedConns := int32(numRequests / 2)
+	minReusedConns := int32(numRequests) - expectedMaxNewConns
 	if reusedConns < minReusedConns {
-		t.Errorf("Too few connections reused: %d (expected ≥ %d). "+
+		t.Errorf("Too few connections reused: %d (expected ≥ %d, i.e. ~%d per node). "+
 			"Connection reuse rate: %.1f%%",
-			reusedConns, minReusedConns, float64(reusedConns)/float64(numRequests)*100)
+			reusedConns, minReusedConns, numRequests/int(nodeCount), float64(reusedConns)/float64(numRequests)*100)
 	}
 }
@@ -6,7 +6,6 @@ package sdkv2_test
 import (
 	"context"
 	"net/http/httptrace"
-	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -16,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	helper "github.com/scylladb/alternator-client-golang/sdkv2"
+	"github.com/scylladb/alternator-client-golang/shared/metrics"
 )
 
 // TestHTTPConnectionReuse verifies that the client reuses HTTP connections
@@ -75,35 +75,23 @@ func testConnectionReuse(t *testing.T, scheme string, port int, ignoreCertErrors
 
 	var newConnCount atomic.Int32
 	var reusedConnCount atomic.Int32
-	var mu sync.Mutex
-	seenConns := make(map[string]bool)
 
-	numRequests := 20
-	for i := 0; i < numRequests; i++ {
-		trace := &httptrace.ClientTrace{
-			GotConn: func(info httptrace.GotConnInfo) {
-				mu.Lock()
-				defer mu.Unlock()
-
-				connKey := info.Conn.LocalAddr().String() + "->" + info.Conn.RemoteAddr().String()
-
-				if info.Reused {
-					reusedConnCount.Add(1)
-					t.Logf("Request %d: Connection REUSED: %s", i, connKey)
-				} else {
-					if seenConns[connKey] {
-						// This is a reconnection (not first time seeing this local->remote pair)
-						t.Logf("Request %d: RECONNECTION detected: %s (connection was closed)", i, connKey)
-					} else {
-						newConnCount.Add(1)
-						seenConns[connKey] = true
-						t.Logf("Request %d: NEW connection: %s", i, connKey)
-					}
-				}
-			},
+	// ConnReuseTracer distinguishes a genuinely new TCP connection from a
+	// request multiplexed onto one that's already open; reading
+	// httptrace.GotConnInfo.Reused directly is unreliable for that under
+	// HTTP/2, since concurrent requests racing to multiplex a stream onto
+	// the same connection can each observe Reused=false.
+	tracer := metrics.NewConnReuseTracer(func(info httptrace.GotConnInfo, streamReused bool) {
+		if streamReused || info.Reused {
+			reusedConnCount.Add(1)
+			return
 		}
+		newConnCount.Add(1)
+	})
 
-		traceCtx := httptrace.WithClientTrace(ctx, trace)
+	numRequests := 20
+	for i := 0; i < numRequests; i++ {
+		traceCtx := httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{GotConn: tracer.GotConn})
 
 		_, err := ddb.GetItem(traceCtx, &dynamodb.GetItemInput{
 			TableName: aws.String(tableName),
@@ -121,36 +109,32 @@ func testConnectionReuse(t *testing.T, scheme string, port int, ignoreCertErrors
 	newConns := newConnCount.Load()
 	reusedConns := reusedConnCount.Load()
 
-	// After the first request establishes connections, most subsequent requests
-	// should reuse connections. Allow for a few new connections due to
-	// load balancing across multiple nodes.
+	// After the first request establishes connections, most subsequent
+	// requests should reuse connections. New connections should scale with
+	// the node count (one or two per node for load-balancing slack)
+	// instead of a single global constant, since each node gets its own
+	// per-host idle pool.
 	//
-	// With 20 requests and 3 alternator nodes, we expect:
-	// - ~3-6 new connections (one or two per node)
-	// - ~14-17 reused connections
-	expectedMaxNewConns := int32(8) // Allow some buffer
+	// With 20 requests and len(knownNodes) alternator nodes, we expect:
+	// - ~1-2 new connections per node
+	// - the remaining requests to reuse a connection
+	nodeCount := int32(len(knownNodes))
+	expectedMaxNewConns := 2 * nodeCount
 
 	if newConns > expectedMaxNewConns {
-		t.Errorf("Too many new connections created: %d (expected ≤ %d). "+
+		t.Errorf("Too many new connections created: %d (expected ≤ %d for %d nodes). "+
 			"This indicates connection reuse is not working properly. "+
-			"Check MaxIdleConnsPerHost setting.",
-			newConns, expectedMaxNewConns)
+			"Check MaxIdleHTTPConnectionsPerHost setting.",
+			newConns, expectedMaxNewConns, nodeCount)
 	}
 
-	// At least half of the requests should reuse connections
-	minReusedConns := int32(numRequests / 2)
+	// The remaining requests, beyond the expected new connections per
+	// node, should reuse a connection.
+	minReusedConns := int32(numRequests) - expectedMaxNewConns
 	if reusedConns < minReusedConns {
-		t.Errorf("Too few connections reused: %d (expected ≥ %d). "+
+		t.Errorf("Too few connections reused: %d (expected ≥ %d, i.e. ~%d per node). "+
 			"Connection reuse rate: %.1f%%. "+
 			"This indicates a connection pooling problem.",
-			reusedConns, minReusedConns, float64(reusedConns)/float64(numRequests)*100)
-	}
-
-	// Verify no excessive reconnections (connection closed and reopened to same remote)
-	// This would indicate idle timeout is too short or connections are being closed prematurely
-	if int32(len(seenConns)) > newConns {
-		t.Errorf("Detected reconnections: saw %d unique connection pairs but only %d were 'new'. "+
-			"This suggests connections are being closed and reopened.",
-			len(seenConns), newConns)
+			reusedConns, minReusedConns, numRequests/int(nodeCount), float64(reusedConns)/float64(numRequests)*100)
 	}
 }
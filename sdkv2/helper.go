@@ -0,0 +1,181 @@
+// Package sdkv2 wires an Alternator-aware HTTP transport into the AWS SDK
+// for Go (v2) DynamoDB client.
+package sdkv2
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/scylladb/alternator-client-golang/shared"
+	"github.com/scylladb/alternator-client-golang/shared/metrics"
+)
+
+// Option configures a Helper.
+type Option func(*config)
+
+type config struct {
+	shared.ALNConfig
+
+	scheme      string
+	port        int
+	accessKeyID string
+	secretKey   string
+
+	nodesListUpdatePeriod     time.Duration
+	idleNodesListUpdatePeriod time.Duration
+}
+
+const defaultIdleHTTPConnectionTimeout = 90 * time.Second
+
+func defaultConfig() config {
+	return config{
+		ALNConfig: shared.ALNConfig{
+			IdleHTTPConnectionTimeout:     defaultIdleHTTPConnectionTimeout,
+			MaxIdleHTTPConnections:        100,
+			MaxIdleHTTPConnectionsPerHost: 100,
+		},
+		scheme:                    "http",
+		port:                      8080,
+		nodesListUpdatePeriod:     time.Minute,
+		idleNodesListUpdatePeriod: 5 * time.Minute,
+	}
+}
+
+// WithScheme sets the URL scheme ("http" or "https") used to reach nodes.
+func WithScheme(scheme string) Option {
+	return func(c *config) { c.scheme = scheme }
+}
+
+// WithPort sets the port used to reach nodes.
+func WithPort(port int) Option {
+	return func(c *config) { c.port = port }
+}
+
+// WithCredentials sets the static AWS credentials used to sign requests.
+func WithCredentials(accessKeyID, secretKey string) Option {
+	return func(c *config) {
+		c.accessKeyID = accessKeyID
+		c.secretKey = secretKey
+	}
+}
+
+// WithNodesListUpdatePeriod sets how often the node list is refreshed.
+// Zero disables the background refresh.
+func WithNodesListUpdatePeriod(period time.Duration) Option {
+	return func(c *config) { c.nodesListUpdatePeriod = period }
+}
+
+// WithIdleNodesListUpdatePeriod records a node-list refresh period for an
+// idle client. The Helper does not use it: the node list is refreshed every
+// NodesListUpdatePeriod whether or not the client is idle.
+func WithIdleNodesListUpdatePeriod(period time.Duration) Option {
+	return func(c *config) { c.idleNodesListUpdatePeriod = period }
+}
+
+// WithIgnoreServerCertificateError disables TLS certificate verification.
+func WithIgnoreServerCertificateError(ignore bool) Option {
+	return func(c *config) { c.IgnoreServerCertificateError = ignore }
+}
+
+// WithIdleHTTPConnectionTimeout sets how long an idle HTTP connection is
+// kept around before being closed.
+func WithIdleHTTPConnectionTimeout(d time.Duration) Option {
+	return func(c *config) { c.IdleHTTPConnectionTimeout = d }
+}
+
+// WithTLSSessionCacheCapacity sizes the default bounded LRU TLS session
+// cache. Zero disables it, so TLS sessions are not resumed.
+func WithTLSSessionCacheCapacity(capacity int) Option {
+	return func(c *config) { c.TLSSessionCacheCapacity = capacity }
+}
+
+// WithReconnectPolicy sets the backoff and quarantine policy driving the
+// node-list updater and its load balancing.
+func WithReconnectPolicy(policy shared.ReconnectPolicy) Option {
+	return func(c *config) { c.ReconnectPolicy = policy }
+}
+
+// WithMetricsRegisterer registers the client's Prometheus collectors
+// against registerer and installs request and connection-reuse metrics
+// instrumentation. Left unset, no metrics are collected.
+func WithMetricsRegisterer(registerer prometheus.Registerer) Option {
+	return func(c *config) { c.MetricsRegisterer = registerer }
+}
+
+// Helper wires an Alternator-aware HTTP transport and node list into an AWS
+// SDK (v2) DynamoDB client.
+type Helper struct {
+	config  config
+	updater *shared.NodeListUpdater
+}
+
+// NewHelper creates a Helper seeded with the known nodes.
+func NewHelper(knownNodes []string, opts ...Option) (*Helper, error) {
+	if len(knownNodes) == 0 {
+		return nil, fmt.Errorf("alternator: at least one known node is required")
+	}
+
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// Built once and shared across every transport this Helper creates: a
+	// given registerer would panic on double registration if each
+	// transport constructed its own collectors.
+	var collectors *metrics.Collectors
+	if cfg.MetricsRegisterer != nil {
+		collectors = metrics.New(cfg.MetricsRegisterer)
+		cfg.Metrics = collectors
+	}
+
+	probeClient := &http.Client{Transport: shared.NewALNHTTPTransport(cfg.ALNConfig)}
+	updater := shared.NewNodeListUpdater(knownNodes, cfg.scheme, probeClient, cfg.ReconnectPolicy)
+	if collectors != nil {
+		updater.OnLiveIdleChange(collectors.SetNodeCounts)
+	}
+	updater.Run(cfg.nodesListUpdatePeriod)
+
+	return &Helper{config: cfg, updater: updater}, nil
+}
+
+// Stop terminates the background node-list refresh.
+func (h *Helper) Stop() {
+	h.updater.Stop()
+}
+
+// NewDynamoDB creates a DynamoDB client pinned to the next known node,
+// round-robin, skipping any the reconnect policy has quarantined. Call
+// NewDynamoDB again, e.g. per-request, to spread load across nodes; each
+// client it returns always talks to the single node it was created for.
+func (h *Helper) NewDynamoDB() (*dynamodb.Client, error) {
+	node, ok := h.updater.NextNode()
+	if !ok {
+		return nil, fmt.Errorf("alternator: no healthy node available, all nodes are quarantined")
+	}
+
+	// node is picked once here and baked into the endpoint below, rather
+	// than rewritten per request by a RoundTripper: AWS SigV4 signs the
+	// Host header before the HTTP client ever sees the request, so
+	// rerouting after signing would invalidate the signature against
+	// every node but whichever one was picked first.
+	transport := shared.NewALNHTTPTransport(h.config.ALNConfig)
+
+	cfg := aws.Config{
+		Region:      "alternator",
+		Credentials: credentials.NewStaticCredentialsProvider(h.config.accessKeyID, h.config.secretKey, ""),
+		HTTPClient:  &http.Client{Transport: transport},
+	}
+
+	endpoint := fmt.Sprintf("%s://%s:%d", h.config.scheme, node, h.config.port)
+	return dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+	}), nil
+}
@@ -0,0 +1,110 @@
+//go:build integration
+// +build integration
+
+package sdkv2_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	helper "github.com/scylladb/alternator-client-golang/sdkv2"
+)
+
+// TestTLSSessionResumption verifies that the default bounded LRU TLS
+// session cache lets a second connection to an Alternator node resume its
+// TLS session instead of performing a full handshake.
+func TestTLSSessionResumption(t *testing.T) {
+	opts := []helper.Option{
+		helper.WithScheme("https"),
+		helper.WithPort(httpsPort),
+		helper.WithNodesListUpdatePeriod(0),
+		helper.WithIdleNodesListUpdatePeriod(0),
+		helper.WithCredentials("whatever", "secret"),
+		helper.WithIgnoreServerCertificateError(true),
+		helper.WithTLSSessionCacheCapacity(32),
+		// Force the idle connection to close between the two requests below,
+		// so the second one performs a fresh TLS handshake rather than
+		// reusing the first request's TCP connection.
+		helper.WithIdleHTTPConnectionTimeout(50 * time.Millisecond),
+	}
+
+	h, err := helper.NewHelper(knownNodes, opts...)
+	if err != nil {
+		t.Fatalf("failed to create alternator helper: %v", err)
+	}
+	defer h.Stop()
+
+	ddb, err := h.NewDynamoDB()
+	if err != nil {
+		t.Fatalf("failed to create DynamoDB client: %v", err)
+	}
+
+	tableName := "tls_session_resumption_test"
+	ctx := context.Background()
+
+	_, err = ddb.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash},
+		},
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	var handshakes atomic.Int32
+	var resumed atomic.Int32
+
+	get := func(i int) {
+		trace := &httptrace.ClientTrace{
+			TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+				if err != nil {
+					return
+				}
+				handshakes.Add(1)
+				if state.DidResume {
+					resumed.Add(1)
+				}
+			},
+		}
+		traceCtx := httptrace.WithClientTrace(ctx, trace)
+
+		_, err := ddb.GetItem(traceCtx, &dynamodb.GetItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: "test-id"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("GetItem request %d error: %v", i, err)
+		}
+	}
+
+	// The first request establishes the TLS session. Waiting past the idle
+	// connection timeout configured above forces the second request onto a
+	// new TCP connection, so its handshake can only be fast via resumption.
+	get(0)
+	time.Sleep(100 * time.Millisecond)
+	get(1)
+
+	if handshakes.Load() < 2 {
+		t.Fatalf("expected at least 2 TLS handshakes, got %d", handshakes.Load())
+	}
+	if resumed.Load() == 0 {
+		t.Errorf("expected the second TLS handshake to resume the cached session, but none did")
+	}
+}
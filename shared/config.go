@@ -0,0 +1,75 @@
+package shared
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scylladb/alternator-client-golang/shared/metrics"
+)
+
+const defaultIdleConnectionTimeout = 90 * time.Second
+
+// Logger is the logging interface implementations can provide to surface
+// diagnostic messages from the client.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// ClientCertificateSource supplies a client certificate for mutual TLS on
+// demand, optionally logging through the configured Logger.
+type ClientCertificateSource interface {
+	GetClientCertificate(info *tls.CertificateRequestInfo, logger Logger) (*tls.Certificate, error)
+}
+
+// ALNConfig holds the configuration shared by the sdkv1 and sdkv2 helpers
+// for talking to an Alternator cluster.
+type ALNConfig struct {
+	// IdleHTTPConnectionTimeout is how long an idle connection is kept
+	// around before being closed.
+	IdleHTTPConnectionTimeout time.Duration
+	// MaxIdleHTTPConnections is the cap on idle connections a single
+	// transport keeps open across all Alternator nodes.
+	MaxIdleHTTPConnections int
+	// MaxIdleHTTPConnectionsPerHost is the cap on idle connections kept
+	// open to a single Alternator node.
+	MaxIdleHTTPConnectionsPerHost int
+	// MaxHTTPConnectionsPerHost is the cap on total (idle + in-use)
+	// connections to a single Alternator node.
+	MaxHTTPConnectionsPerHost int
+
+	// HTTP2 configures explicit HTTP/2 support. Without it, HTTP/2 is only
+	// negotiated opportunistically via TLS ALPN.
+	HTTP2 HTTP2Mode
+
+	// ReconnectPolicy controls backoff and quarantine for the node-list
+	// updater when a node used for discovery is unreachable or errors.
+	ReconnectPolicy ReconnectPolicy
+
+	// MetricsRegisterer, when set, registers the client's Prometheus
+	// collectors (see the `shared/metrics` package) and installs
+	// connection-reuse and per-request RoundTripper instrumentation. Left
+	// nil, no metrics are collected.
+	MetricsRegisterer prometheus.Registerer
+	// Metrics, when set, is used instead of constructing new collectors
+	// from MetricsRegisterer. Callers that build more than one transport
+	// for the same client (e.g. a probe client alongside the main one) must
+	// construct this once with `metrics.New` and share it across every
+	// ALNConfig passed to NewALNHTTPTransport, since registering the same
+	// collectors against MetricsRegisterer twice panics.
+	Metrics *metrics.Collectors
+
+	KeyLogWriter                 io.Writer
+	IgnoreServerCertificateError bool
+	TLSSessionCache              tls.ClientSessionCache
+	// TLSSessionCacheCapacity sizes the default bounded LRU TLSSessionCache
+	// created when TLSSessionCache is nil. Zero disables the default cache;
+	// with TLSSessionCache also nil, TLS sessions are then not resumed.
+	TLSSessionCacheCapacity int
+	ClientCertificateSource ClientCertificateSource
+	Logger                  Logger
+	HTTPTransportWrapper    func(http.RoundTripper) http.RoundTripper
+}
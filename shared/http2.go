@@ -0,0 +1,176 @@
+package shared
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// HTTP2Mode configures explicit HTTP/2 support for the Alternator HTTP
+// transport.
+type HTTP2Mode struct {
+	// Enabled turns on explicit HTTP/2 support via `http2.ConfigureTransports`.
+	// Without it, HTTP/2 is only negotiated opportunistically when TLS ALPN
+	// picks it, and never for cleartext connections.
+	Enabled bool
+	// AllowCleartext enables HTTP/2 over cleartext connections (h2c), for
+	// Alternator deployments reached without TLS.
+	AllowCleartext bool
+	// MaxConcurrentStreams caps the number of requests the transport has in
+	// flight at once, counted across all of its connections and hosts
+	// rather than per connection; requests beyond the cap wait for one in
+	// flight to finish. Zero means no client-side cap.
+	MaxConcurrentStreams uint32
+	// PingTimeout is how long to wait for a health-check PING response
+	// before the connection is considered dead.
+	PingTimeout time.Duration
+	// ReadIdleTimeout is how often to send a health-check PING on an
+	// otherwise idle HTTP/2 connection.
+	ReadIdleTimeout time.Duration
+	// WriteByteTimeout is the write deadline for a single byte written to
+	// the connection before it's considered dead.
+	WriteByteTimeout time.Duration
+}
+
+// configureHTTP2 enables HTTP/2 on transport according to config.HTTP2 and
+// returns the RoundTripper requests should be sent through. When h2c is
+// requested, transport's cleartext dialing is swapped out for one that
+// fakes a TLS handshake, since net/http never calls DialTLS for plain
+// "http" URLs.
+func configureHTTP2(config ALNConfig, transport *http.Transport) (http.RoundTripper, error) {
+	if !config.HTTP2.Enabled {
+		return transport, nil
+	}
+
+	h2Transport, err := http2.ConfigureTransports(transport)
+	if err != nil {
+		return nil, err
+	}
+	h2Transport.PingTimeout = config.HTTP2.PingTimeout
+	h2Transport.ReadIdleTimeout = config.HTTP2.ReadIdleTimeout
+	h2Transport.WriteByteTimeout = config.HTTP2.WriteByteTimeout
+
+	var roundTripper http.RoundTripper = transport
+
+	if config.HTTP2.AllowCleartext {
+		h2Transport.AllowHTTP = true
+
+		dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+		if config.MaxHTTPConnectionsPerHost > 0 {
+			// h2c traffic is dialed directly by h2Transport below, which
+			// has no per-host connection cap of its own (unlike
+			// `*http.Transport`, which transport.MaxConnsPerHost normally
+			// governs), so it's enforced here instead.
+			dial = newPerHostDialLimiter(config.MaxHTTPConnectionsPerHost).wrap(dial)
+		}
+		h2Transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dial(ctx, network, addr)
+		}
+
+		// `*http.Transport` only ever calls (Dial)TLS for "https" requests,
+		// so ALPN-based negotiation via `transport` never applies to h2c
+		// ("http" with HTTP/2) traffic; it must go directly through
+		// h2Transport instead, which is what actually owns AllowHTTP and
+		// the cleartext DialTLSContext above.
+		roundTripper = h2Transport
+	}
+
+	if config.HTTP2.MaxConcurrentStreams != 0 {
+		roundTripper = newStreamLimitedTransport(roundTripper, config.HTTP2.MaxConcurrentStreams)
+	}
+	return roundTripper, nil
+}
+
+// streamLimitedTransport caps the number of requests in flight at once,
+// standing in for a client-side SETTINGS_MAX_CONCURRENT_STREAMS since the
+// server is free to advertise (or not enforce) whatever limit it likes.
+type streamLimitedTransport struct {
+	next http.RoundTripper
+	sem  chan struct{}
+}
+
+func newStreamLimitedTransport(next http.RoundTripper, maxConcurrentStreams uint32) *streamLimitedTransport {
+	return &streamLimitedTransport{
+		next: next,
+		sem:  make(chan struct{}, maxConcurrentStreams),
+	}
+}
+
+func (t *streamLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-t.sem }()
+	return t.next.RoundTrip(req)
+}
+
+// perHostDialLimiter caps the number of open connections a dial func is
+// allowed to hold per host, by address. It exists for the h2c dial path:
+// unlike `*http.Transport`, `*http2.Transport` pools connections per host
+// but has no cap on how many it opens, so MaxHTTPConnectionsPerHost would
+// otherwise go unenforced for cleartext HTTP/2 traffic.
+type perHostDialLimiter struct {
+	limit int
+
+	mu   sync.Mutex
+	sems map[string]chan struct{}
+}
+
+func newPerHostDialLimiter(limit int) *perHostDialLimiter {
+	return &perHostDialLimiter{limit: limit, sems: make(map[string]chan struct{})}
+}
+
+func (l *perHostDialLimiter) wrap(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		sem := l.semFor(addr)
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			<-sem
+			return nil, err
+		}
+		return &releaseOnCloseConn{Conn: conn, release: func() { <-sem }}, nil
+	}
+}
+
+func (l *perHostDialLimiter) semFor(addr string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sem, ok := l.sems[addr]
+	if !ok {
+		sem = make(chan struct{}, l.limit)
+		l.sems[addr] = sem
+	}
+	return sem
+}
+
+// releaseOnCloseConn frees a perHostDialLimiter slot the first time the
+// connection is closed.
+type releaseOnCloseConn struct {
+	net.Conn
+
+	once    sync.Once
+	release func()
+}
+
+func (c *releaseOnCloseConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
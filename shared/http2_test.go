@@ -0,0 +1,168 @@
+package shared
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// TestConfigureHTTP2Cleartext verifies that enabling AllowCleartext routes
+// requests directly through the *http2.Transport with a cleartext
+// DialTLSContext, rather than through the *http.Transport passed to
+// http2.ConfigureTransports, which never calls DialTLSContext for "http"
+// URLs and would otherwise attempt a real TLS handshake.
+func TestConfigureHTTP2Cleartext(t *testing.T) {
+	config := ALNConfig{
+		HTTP2: HTTP2Mode{
+			Enabled:        true,
+			AllowCleartext: true,
+		},
+	}
+
+	roundTripper, err := configureHTTP2(config, DefaultHTTPTransport())
+	if err != nil {
+		t.Fatalf("configureHTTP2 returned error: %v", err)
+	}
+
+	h2Transport, ok := roundTripper.(*http2.Transport)
+	if !ok {
+		t.Fatalf("configureHTTP2 returned %T, want *http2.Transport for h2c", roundTripper)
+	}
+	if !h2Transport.AllowHTTP {
+		t.Error("AllowHTTP = false, want true when AllowCleartext is set")
+	}
+	if h2Transport.DialTLSContext == nil {
+		t.Fatal("DialTLSContext is nil, want a cleartext dialer")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	conn, err := h2Transport.DialTLSContext(context.Background(), "tcp", ln.Addr().String(), nil)
+	if err != nil {
+		t.Fatalf("DialTLSContext returned error for a plain TCP listener: %v", err)
+	}
+	conn.Close()
+}
+
+// TestConfigureHTTP2ConcurrentStreamsLimit verifies that a
+// MaxConcurrentStreams cap blocks a request until an in-flight one
+// completes, rather than letting every request through unconditionally.
+func TestConfigureHTTP2ConcurrentStreamsLimit(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		started <- struct{}{}
+		<-release
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	limited := newStreamLimitedTransport(base, 1)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+
+	done := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, _ = limited.RoundTrip(req)
+			done <- struct{}{}
+		}()
+	}
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("first request never started")
+	}
+
+	select {
+	case <-started:
+		t.Fatal("second concurrent request started despite MaxConcurrentStreams=1")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+	<-done
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// TestPerHostDialLimiterCapsConnections verifies that a second dial to the
+// same host blocks until an earlier connection to that host is closed, and
+// that a dial to a different host is unaffected.
+func TestPerHostDialLimiterCapsConnections(t *testing.T) {
+	dialing := make(chan struct{}, 3)
+	release := make(chan struct{})
+	dial := func(_ context.Context, _, addr string) (net.Conn, error) {
+		dialing <- struct{}{}
+		<-release
+		client, server := net.Pipe()
+		go server.Close()
+		return client, nil
+	}
+
+	limited := newPerHostDialLimiter(1).wrap(dial)
+
+	done := make(chan net.Conn, 1)
+	go func() {
+		conn, _ := limited(context.Background(), "tcp", "node-0:8080")
+		done <- conn
+	}()
+
+	select {
+	case <-dialing:
+	case <-time.After(time.Second):
+		t.Fatal("first dial to node-0 never started")
+	}
+
+	second := make(chan struct{})
+	go func() {
+		_, _ = limited(context.Background(), "tcp", "node-0:8080")
+		close(second)
+	}()
+
+	select {
+	case <-second:
+		t.Fatal("second dial to node-0 completed despite the per-host limit of 1")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	go func() {
+		_, _ = limited(context.Background(), "tcp", "node-1:8080")
+	}()
+
+	select {
+	case <-dialing:
+	case <-time.After(time.Second):
+		t.Fatal("dial to node-1 was blocked by the per-host limit of node-0")
+	}
+
+	close(release)
+	conn := <-done
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	select {
+	case <-second:
+	case <-time.After(time.Second):
+		t.Fatal("second dial to node-0 never unblocked after the first connection closed")
+	}
+}
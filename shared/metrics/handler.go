@@ -0,0 +1,16 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler returns the `/metrics` HTTP handler serving gatherer. Pass the
+// `prometheus.Gatherer` backing the `prometheus.Registerer` given to
+// `ALNConfig.MetricsRegisterer` (e.g. the same `*prometheus.Registry`) so
+// the handler serves exactly the collectors registered for this client.
+func Handler(gatherer prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
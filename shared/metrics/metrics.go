@@ -0,0 +1,91 @@
+// Package metrics provides an optional Prometheus observability surface for
+// the Alternator client: per-node request outcomes, round-trip latency, the
+// live/idle node counts tracked by the node-list updater, and connection
+// reuse. Nothing here is wired up unless an `ALNConfig.MetricsRegisterer` is
+// configured.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Request outcome labels for RequestsTotal.
+const (
+	OutcomeSent    = "sent"
+	OutcomeRetried = "retried"
+	OutcomeFailed  = "failed"
+)
+
+// Node state labels for Nodes.
+const (
+	NodeStateLive = "live"
+	NodeStateIdle = "idle"
+)
+
+// Collectors holds the Prometheus collectors registered for a single
+// Alternator client. A nil `prometheus.Registerer` passed to New produces
+// collectors that are safe to use but never exposed on a `/metrics`
+// endpoint.
+type Collectors struct {
+	RequestsTotal    *prometheus.CounterVec
+	RequestDuration  *prometheus.HistogramVec
+	Nodes            *prometheus.GaugeVec
+	ConnectionsTotal *prometheus.CounterVec
+}
+
+// New creates and, if registerer is non-nil, registers the collectors used
+// by the Alternator client.
+func New(registerer prometheus.Registerer) *Collectors {
+	factory := promauto.With(registerer)
+
+	return &Collectors{
+		RequestsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "alternator",
+			Name:      "requests_total",
+			Help:      "Total number of requests by node and outcome (sent, retried, failed).",
+		}, []string{"node", "outcome"}),
+		RequestDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "alternator",
+			Name:      "request_duration_seconds",
+			Help:      "Round-trip latency of requests, by node and operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"node", "operation"}),
+		Nodes: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "alternator",
+			Name:      "nodes",
+			Help:      "Current number of nodes known to the node-list updater, by state (live, idle).",
+		}, []string{"state"}),
+		ConnectionsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "alternator",
+			Name:      "connections_total",
+			Help:      "Total number of connection events, by kind (new, reused) and level (tcp, http2_stream).",
+		}, []string{"kind", "level"}),
+	}
+}
+
+// ObserveRequest records the outcome and latency of a single request against
+// node.
+func (c *Collectors) ObserveRequest(node, operation, outcome string, duration time.Duration) {
+	c.RequestsTotal.WithLabelValues(node, outcome).Inc()
+	c.RequestDuration.WithLabelValues(node, operation).Observe(duration.Seconds())
+}
+
+// SetNodeCounts reports the current live and idle node counts, as tracked by
+// the node-list updater.
+func (c *Collectors) SetNodeCounts(live, idle int) {
+	c.Nodes.WithLabelValues(NodeStateLive).Set(float64(live))
+	c.Nodes.WithLabelValues(NodeStateIdle).Set(float64(idle))
+}
+
+// observeConnection records a new-connection or reused-connection event for
+// the given reuse level ("tcp" or "http2_stream").
+func (c *Collectors) observeConnection(reused bool, level string) {
+	kind := "new"
+	if reused {
+		kind = "reused"
+	}
+	c.ConnectionsTotal.WithLabelValues(kind, level).Inc()
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// InstrumentRoundTripper wraps next so every request records a connection
+// reuse event against collectors: a "tcp" level event the first time a
+// connection is used, and an "http2_stream" event for every subsequent
+// request multiplexed onto that same connection, via a ConnReuseTracer
+// shared across every request routed through the returned RoundTripper.
+func InstrumentRoundTripper(next http.RoundTripper, collectors *Collectors) http.RoundTripper {
+	t := &instrumentedTransport{next: next, collectors: collectors}
+	t.tracer = NewConnReuseTracer(func(info httptrace.GotConnInfo, streamReused bool) {
+		level := "tcp"
+		reused := info.Reused
+		if streamReused {
+			level = "http2_stream"
+			reused = true
+		}
+		t.collectors.observeConnection(reused, level)
+	})
+	return t
+}
+
+type instrumentedTransport struct {
+	next       http.RoundTripper
+	collectors *Collectors
+	tracer     *ConnReuseTracer
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := &httptrace.ClientTrace{GotConn: t.tracer.GotConn}
+	ctx := httptrace.WithClientTrace(req.Context(), trace)
+	return t.next.RoundTrip(req.WithContext(ctx))
+}
+
+// ConnReuseTracer wraps an httptrace.ClientTrace GotConn hook so connection
+// reuse is reported correctly under HTTP/2. net/http's own
+// httptrace.GotConnInfo.Reused is unreliable there: concurrent requests
+// racing to multiplex a stream onto the same connection can each observe
+// Reused=false, because the "this connection already served a request"
+// bookkeeping is only updated after GotConn fires. ConnReuseTracer tracks
+// first use per connection itself with an atomic CompareAndSwap flag keyed
+// by the local/remote address pair, so callers can tell a genuinely new TCP
+// connection from a stream multiplexed onto one that's already open. Since
+// a ConnReuseTracer doesn't own the connection's lifecycle and so can't
+// intercept Close directly, each entry is evicted once the connection
+// itself is garbage collected.
+type ConnReuseTracer struct {
+	seen sync.Map // string (local->remote) -> *int32
+
+	// Next is called once per GotConn, with streamReused set to true for
+	// every call after the first one observed for a given connection.
+	Next func(info httptrace.GotConnInfo, streamReused bool)
+}
+
+// NewConnReuseTracer returns a ConnReuseTracer that calls next for every
+// GotConn event, with an HTTP/2-aware reuse signal.
+func NewConnReuseTracer(next func(info httptrace.GotConnInfo, streamReused bool)) *ConnReuseTracer {
+	return &ConnReuseTracer{Next: next}
+}
+
+// GotConn is an httptrace.ClientTrace.GotConn hook.
+func (c *ConnReuseTracer) GotConn(info httptrace.GotConnInfo) {
+	key := info.Conn.LocalAddr().String() + "->" + info.Conn.RemoteAddr().String()
+	v, loaded := c.seen.LoadOrStore(key, new(int32))
+	firstUse := atomic.CompareAndSwapInt32(v.(*int32), 0, 1)
+	if !loaded {
+		runtime.SetFinalizer(info.Conn, func(net.Conn) { c.seen.Delete(key) })
+	}
+	if c.Next != nil {
+		c.Next(info, !firstUse)
+	}
+}
+
+// InstrumentRequestRoundTripper wraps next so every request's outcome and
+// latency are recorded against collectors. The node label is the request's
+// target host, read after any load-balancing wrapper has rewritten it; the
+// operation label is the DynamoDB action name from the `X-Amz-Target`
+// header.
+func InstrumentRequestRoundTripper(next http.RoundTripper, collectors *Collectors) http.RoundTripper {
+	return &requestInstrumentedTransport{next: next, collectors: collectors}
+}
+
+type requestInstrumentedTransport struct {
+	next       http.RoundTripper
+	collectors *Collectors
+}
+
+func (t *requestInstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	node := req.URL.Host
+	operation := operationFromTarget(req.Header.Get("X-Amz-Target"))
+	retry := isRetryAttempt(req)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	outcome := OutcomeSent
+	switch {
+	case err != nil:
+		outcome = OutcomeFailed
+	case retry:
+		outcome = OutcomeRetried
+	}
+	t.collectors.ObserveRequest(node, operation, outcome, duration)
+
+	return resp, err
+}
+
+// operationFromTarget extracts the DynamoDB action name (e.g. "GetItem")
+// from an X-Amz-Target header value (e.g. "DynamoDB_20120810.GetItem").
+func operationFromTarget(target string) string {
+	if i := strings.LastIndexByte(target, '.'); i >= 0 {
+		return target[i+1:]
+	}
+	return target
+}
+
+// isRetryAttempt reports whether req is a retry of an earlier attempt, per
+// the AWS SDK's own "Amz-Sdk-Request: attempt=N; ..." header.
+func isRetryAttempt(req *http.Request) bool {
+	for _, part := range strings.Split(req.Header.Get("Amz-Sdk-Request"), ";") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "attempt=") {
+			return strings.TrimPrefix(part, "attempt=") != "1"
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+type fakeAddr string
+
+func (a fakeAddr) Network() string { return "tcp" }
+func (a fakeAddr) String() string  { return string(a) }
+
+// fakeConn satisfies net.Conn well enough for ConnReuseTracer.GotConn, which
+// only ever calls LocalAddr/RemoteAddr.
+type fakeConn struct {
+	net.Conn
+	local, remote fakeAddr
+}
+
+func (c *fakeConn) LocalAddr() net.Addr  { return c.local }
+func (c *fakeConn) RemoteAddr() net.Addr { return c.remote }
+
+func TestConnReuseTracerFirstUseThenStreamReuse(t *testing.T) {
+	var reuseFlags []bool
+	tracer := NewConnReuseTracer(func(_ httptrace.GotConnInfo, streamReused bool) {
+		reuseFlags = append(reuseFlags, streamReused)
+	})
+
+	conn := &fakeConn{local: "127.0.0.1:1", remote: "127.0.0.1:2"}
+	tracer.GotConn(httptrace.GotConnInfo{Conn: conn})
+	tracer.GotConn(httptrace.GotConnInfo{Conn: conn})
+
+	want := []bool{false, true}
+	if len(reuseFlags) != len(want) || reuseFlags[0] != want[0] || reuseFlags[1] != want[1] {
+		t.Errorf("streamReused per call = %v, want %v", reuseFlags, want)
+	}
+}
+
+func TestConnReuseTracerEvictsEntryOnConnGC(t *testing.T) {
+	tracer := NewConnReuseTracer(nil)
+	const key = "127.0.0.1:1->127.0.0.1:2"
+
+	func() {
+		conn := &fakeConn{local: "127.0.0.1:1", remote: "127.0.0.1:2"}
+		tracer.GotConn(httptrace.GotConnInfo{Conn: conn})
+	}()
+
+	if _, ok := tracer.seen.Load(key); !ok {
+		t.Fatal("expected a seen entry right after GotConn")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		runtime.GC()
+		if _, ok := tracer.seen.Load(key); !ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("seen entry was never evicted after its connection was garbage collected")
+}
+
+func TestInstrumentRequestRoundTripperRecordsOutcome(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	collectors := New(registry)
+
+	tests := []struct {
+		name        string
+		sdkRequest  string
+		roundTrip   roundTripFunc
+		wantOutcome string
+	}{
+		{
+			name:       "first attempt succeeds",
+			sdkRequest: "attempt=1; max=3",
+			roundTrip: func(req *http.Request) (*http.Response, error) {
+				return httptest.NewRecorder().Result(), nil
+			},
+			wantOutcome: OutcomeSent,
+		},
+		{
+			name:       "second attempt is a retry",
+			sdkRequest: "attempt=2; max=3",
+			roundTrip: func(req *http.Request) (*http.Response, error) {
+				return httptest.NewRecorder().Result(), nil
+			},
+			wantOutcome: OutcomeRetried,
+		},
+		{
+			name:       "transport error fails",
+			sdkRequest: "attempt=1; max=3",
+			roundTrip: func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			},
+			wantOutcome: OutcomeFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := InstrumentRequestRoundTripper(tt.roundTrip, collectors)
+
+			req, _ := http.NewRequest(http.MethodPost, "http://node-0/", nil)
+			req.Header.Set("X-Amz-Target", "DynamoDB_20120810.GetItem")
+			req.Header.Set("Amz-Sdk-Request", tt.sdkRequest)
+
+			transport.RoundTrip(req) //nolint:errcheck
+
+			got := testutil.ToFloat64(collectors.RequestsTotal.WithLabelValues("node-0", tt.wantOutcome))
+			if got != 1 {
+				t.Errorf("RequestsTotal{node=node-0,outcome=%s} = %v, want 1", tt.wantOutcome, got)
+			}
+		})
+	}
+}
+
+func TestOperationFromTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{target: "DynamoDB_20120810.GetItem", want: "GetItem"},
+		{target: "DynamoDB_20120810.PutItem", want: "PutItem"},
+		{target: "", want: ""},
+		{target: "NoDot", want: "NoDot"},
+	}
+
+	for _, tt := range tests {
+		if got := operationFromTarget(tt.target); got != tt.want {
+			t.Errorf("operationFromTarget(%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FetchNodeList retrieves the current cluster node list from node's
+// `/localnodes` endpoint, the same endpoint ProbeNode uses to check
+// liveness.
+func FetchNodeList(ctx context.Context, client *http.Client, scheme, node string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+node+"/localnodes", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("alternator: node list refresh against %s returned status %d", node, resp.StatusCode)
+	}
+
+	var nodes []string
+	if err := json.NewDecoder(resp.Body).Decode(&nodes); err != nil {
+		return nil, fmt.Errorf("alternator: decoding node list from %s: %w", node, err)
+	}
+	return nodes, nil
+}
+
+// NodeListUpdater owns the set of known Alternator nodes, refreshes it on a
+// schedule, and tracks per-node health via ReconnectPolicy. It also acts as
+// the load balancer: NextNode skips nodes the policy has quarantined.
+type NodeListUpdater struct {
+	mu     sync.Mutex
+	nodes  []string
+	health map[string]*NodeHealth
+	rr     int
+
+	policy ReconnectPolicy
+	scheme string
+	client *http.Client
+
+	onLiveIdleChange func(live, idle int)
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewNodeListUpdater creates a NodeListUpdater seeded with the known nodes.
+func NewNodeListUpdater(nodes []string, scheme string, client *http.Client, policy ReconnectPolicy) *NodeListUpdater {
+	u := &NodeListUpdater{
+		nodes:  append([]string(nil), nodes...),
+		health: make(map[string]*NodeHealth, len(nodes)),
+		policy: policy,
+		scheme: scheme,
+		client: client,
+		stop:   make(chan struct{}),
+	}
+	for _, n := range nodes {
+		u.health[n] = NewNodeHealth(n, policy)
+	}
+	return u
+}
+
+// OnLiveIdleChange registers fn to be called, with the lock held, whenever
+// the live/quarantined node counts may have changed, e.g. to feed
+// metrics.Collectors.SetNodeCounts.
+func (u *NodeListUpdater) OnLiveIdleChange(fn func(live, idle int)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.onLiveIdleChange = fn
+}
+
+// Run refreshes the node list against the cluster every period until Stop
+// is called. Every candidate node is probed each refresh (not just until
+// the first success), and its health is recorded via ReconnectPolicy
+// individually, so one flaky node behind a healthy one still gets
+// quarantined. A refresh that fails against every candidate is retried
+// after ReconnectPolicy.Backoff rather than waiting a full period;
+// quarantined nodes are probed on every tick so they can recover. Run is a
+// no-op if period is zero.
+func (u *NodeListUpdater) Run(period time.Duration) {
+	if period <= 0 {
+		return
+	}
+
+	u.wg.Add(1)
+	go func() {
+		defer u.wg.Done()
+		attempt := 0
+		for {
+			if nodes, err := u.refreshOnce(); err != nil {
+				attempt++
+			} else {
+				attempt = 0
+				u.setNodes(nodes)
+			}
+			u.probeQuarantined()
+
+			delay := period
+			if attempt > 0 {
+				delay = u.policy.Backoff(attempt - 1)
+			}
+			select {
+			case <-time.After(delay):
+			case <-u.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop terminates the update loop started by Run.
+func (u *NodeListUpdater) Stop() {
+	select {
+	case <-u.stop:
+	default:
+		close(u.stop)
+	}
+	u.wg.Wait()
+}
+
+// NextNode returns the next node to send a request to, round-robin,
+// skipping any the policy has quarantined. It returns ok=false if every
+// known node is quarantined.
+func (u *NodeListUpdater) NextNode() (node string, ok bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	n := len(u.nodes)
+	for i := 0; i < n; i++ {
+		u.rr = (u.rr + 1) % n
+		candidate := u.nodes[u.rr]
+		if u.health[candidate].State() != NodeStateQuarantined {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// refreshOnce probes every known candidate node, recording success or
+// failure against that specific node's health, and returns the node list
+// from the first candidate that answered rather than stopping early: a
+// candidate skipped after the first success would never have its health
+// recorded, and a flaky node behind a healthy one would never be
+// quarantined.
+func (u *NodeListUpdater) refreshOnce() ([]string, error) {
+	u.mu.Lock()
+	candidates := append([]string(nil), u.nodes...)
+	u.mu.Unlock()
+
+	var nodes []string
+	var lastErr error
+	for _, n := range candidates {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		result, err := FetchNodeList(ctx, u.client, u.scheme, n)
+		cancel()
+
+		u.recordHealth(n, err)
+
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if nodes == nil {
+			nodes = result
+		}
+	}
+
+	if nodes != nil {
+		return nodes, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("alternator: no nodes available to refresh node list against")
+	}
+	return nil, lastErr
+}
+
+// recordHealth records the outcome of contacting node against its own
+// NodeHealth.
+func (u *NodeListUpdater) recordHealth(node string, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	h, ok := u.health[node]
+	if !ok {
+		h = NewNodeHealth(node, u.policy)
+		u.health[node] = h
+	}
+	if err != nil {
+		h.RecordFailure(time.Now())
+	} else {
+		h.RecordSuccess()
+	}
+	u.reportCountsLocked()
+}
+
+func (u *NodeListUpdater) setNodes(nodes []string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.nodes = append([]string(nil), nodes...)
+	for _, n := range nodes {
+		if _, ok := u.health[n]; !ok {
+			u.health[n] = NewNodeHealth(n, u.policy)
+		}
+	}
+	u.reportCountsLocked()
+}
+
+func (u *NodeListUpdater) probeQuarantined() {
+	u.mu.Lock()
+	var quarantined []string
+	for n, h := range u.health {
+		if h.State() == NodeStateQuarantined {
+			quarantined = append(quarantined, n)
+		}
+	}
+	u.mu.Unlock()
+
+	for _, n := range quarantined {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := ProbeNode(ctx, u.client, u.scheme, n)
+		cancel()
+
+		u.mu.Lock()
+		h := u.health[n]
+		u.mu.Unlock()
+		if h == nil {
+			continue
+		}
+		if err != nil {
+			h.RecordFailure(time.Now())
+		} else {
+			h.RecordSuccess()
+		}
+	}
+
+	u.mu.Lock()
+	u.reportCountsLocked()
+	u.mu.Unlock()
+}
+
+// reportCountsLocked must be called with u.mu held.
+func (u *NodeListUpdater) reportCountsLocked() {
+	if u.onLiveIdleChange == nil {
+		return
+	}
+	live, idle := 0, 0
+	for _, h := range u.health {
+		if h.State() == NodeStateQuarantined {
+			idle++
+		} else {
+			live++
+		}
+	}
+	u.onLiveIdleChange(live, idle)
+}
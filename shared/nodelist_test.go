@@ -0,0 +1,67 @@
+package shared
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestNodeListUpdaterRefreshOnceQuarantinesOnlyBadNode verifies that a
+// single flaky node behind a healthy one is quarantined on its own, instead
+// of refreshOnce only recording a failure once every candidate has failed.
+func TestNodeListUpdaterRefreshOnceQuarantinesOnlyBadNode(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer bad.Close()
+
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`["node-a","node-b"]`)) //nolint:errcheck
+	}))
+	defer good.Close()
+
+	badAddr := strings.TrimPrefix(bad.URL, "http://")
+	goodAddr := strings.TrimPrefix(good.URL, "http://")
+
+	policy := ReconnectPolicy{MaxConsecutiveFailures: 1}
+	u := NewNodeListUpdater([]string{badAddr, goodAddr}, "http", good.Client(), policy)
+
+	if _, err := u.refreshOnce(); err != nil {
+		t.Fatalf("refreshOnce returned error: %v", err)
+	}
+
+	u.mu.Lock()
+	badState := u.health[badAddr].State()
+	goodState := u.health[goodAddr].State()
+	u.mu.Unlock()
+
+	if badState != NodeStateQuarantined {
+		t.Errorf("bad node state = %s, want quarantined", badState)
+	}
+	if goodState != NodeStateHealthy {
+		t.Errorf("good node state = %s, want healthy, since only the bad node failed", goodState)
+	}
+}
+
+// TestNodeListUpdaterRefreshOnceUsesFirstSuccessfulList verifies refreshOnce
+// still returns the first successful candidate's node list, after probing
+// every candidate for health.
+func TestNodeListUpdaterRefreshOnceUsesFirstSuccessfulList(t *testing.T) {
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`["node-a","node-b"]`)) //nolint:errcheck
+	}))
+	defer good.Close()
+
+	goodAddr := strings.TrimPrefix(good.URL, "http://")
+
+	u := NewNodeListUpdater([]string{goodAddr}, "http", good.Client(), ReconnectPolicy{})
+
+	nodes, err := u.refreshOnce()
+	if err != nil {
+		t.Fatalf("refreshOnce returned error: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0] != "node-a" || nodes[1] != "node-b" {
+		t.Errorf("refreshOnce nodes = %v, want [node-a node-b]", nodes)
+	}
+}
@@ -0,0 +1,186 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// NodeState describes the health of a single Alternator node as tracked by
+// the node-list updater's reconnect policy.
+type NodeState int
+
+const (
+	// NodeStateHealthy is a node the load balancer may pick.
+	NodeStateHealthy NodeState = iota
+	// NodeStateQuarantined is a node the load balancer skips until a
+	// background probe succeeds.
+	NodeStateQuarantined
+)
+
+// String implements fmt.Stringer.
+func (s NodeState) String() string {
+	switch s {
+	case NodeStateHealthy:
+		return "healthy"
+	case NodeStateQuarantined:
+		return "quarantined"
+	default:
+		return "unknown"
+	}
+}
+
+// ReconnectPolicy controls how the node-list updater backs off from, and
+// recovers from, a node it can't refresh against.
+type ReconnectPolicy struct {
+	// InitialBackoff is the delay before the first retry. Defaults to 1s.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries. Defaults to 30s. It also
+	// bounds how long a node's failure count is remembered: a failure
+	// older than MaxBackoff no longer counts towards quarantine.
+	MaxBackoff time.Duration
+	// Multiplier scales the backoff after each consecutive failure.
+	// Defaults to 2; any value of 1 or less is replaced by 2.
+	Multiplier float64
+	// Jitter enables full-jitter randomization of the backoff delay
+	// (`rand(0, min(MaxBackoff, InitialBackoff*Multiplier^attempt))`)
+	// instead of a deterministic capped exponential delay.
+	Jitter bool
+	// MaxConsecutiveFailures is how many consecutive failures quarantine a
+	// node. Zero disables quarantine.
+	MaxConsecutiveFailures int
+	// OnNodeStateChange, if set, is called whenever a node transitions
+	// into or out of quarantine.
+	OnNodeStateChange func(node string, oldState, newState NodeState)
+}
+
+// Backoff returns how long to wait before the next refresh attempt, given
+// how many consecutive failures have happened so far (0 = first retry).
+func (p ReconnectPolicy) Backoff(attempt int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = time.Second
+	}
+	multiplier := p.Multiplier
+	if multiplier <= 1 {
+		multiplier = 2
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+
+	capped := float64(initial) * math.Pow(multiplier, float64(attempt))
+	if capped > float64(maxBackoff) {
+		capped = float64(maxBackoff)
+	}
+	if !p.Jitter {
+		return time.Duration(capped)
+	}
+	return time.Duration(rand.Int63n(int64(capped) + 1))
+}
+
+// NodeHealth tracks consecutive-failure based health for a single node and
+// invokes the owning ReconnectPolicy's OnNodeStateChange callback whenever
+// the node's state flips.
+type NodeHealth struct {
+	mu sync.Mutex
+
+	node                string
+	policy              ReconnectPolicy
+	consecutiveFailures int
+	lastFailure         time.Time
+	state               NodeState
+}
+
+// NewNodeHealth returns a NodeHealth tracker for node, starting out healthy.
+func NewNodeHealth(node string, policy ReconnectPolicy) *NodeHealth {
+	return &NodeHealth{node: node, policy: policy, state: NodeStateHealthy}
+}
+
+// RecordFailure registers a failed refresh or probe against the node at
+// now. A failure more than MaxBackoff after the previous one resets the
+// consecutive-failure count, since a node that's been fine since then
+// shouldn't be punished for an old blip. Once the count reaches
+// MaxConsecutiveFailures, the node is quarantined.
+func (h *NodeHealth) RecordFailure(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.lastFailure.IsZero() && now.Sub(h.lastFailure) > h.decayWindow() {
+		h.consecutiveFailures = 0
+	}
+	h.consecutiveFailures++
+	h.lastFailure = now
+
+	if h.policy.MaxConsecutiveFailures > 0 && h.consecutiveFailures >= h.policy.MaxConsecutiveFailures {
+		h.setState(NodeStateQuarantined)
+	}
+}
+
+// RecordSuccess registers a successful refresh or probe against the node,
+// resetting its failure count and lifting quarantine.
+func (h *NodeHealth) RecordSuccess() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.consecutiveFailures = 0
+	h.setState(NodeStateHealthy)
+}
+
+// State reports the node's current state.
+func (h *NodeHealth) State() NodeState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.state
+}
+
+// ConsecutiveFailures reports the current consecutive-failure count, for
+// computing the next refresh attempt's delay via ReconnectPolicy.Backoff.
+func (h *NodeHealth) ConsecutiveFailures() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.consecutiveFailures
+}
+
+func (h *NodeHealth) decayWindow() time.Duration {
+	if h.policy.MaxBackoff > 0 {
+		return h.policy.MaxBackoff
+	}
+	return 30 * time.Second
+}
+
+func (h *NodeHealth) setState(newState NodeState) {
+	if h.state == newState {
+		return
+	}
+	oldState := h.state
+	h.state = newState
+	if h.policy.OnNodeStateChange != nil {
+		h.policy.OnNodeStateChange(h.node, oldState, newState)
+	}
+}
+
+// ProbeNode issues a lightweight GET against node's `/localnodes` endpoint,
+// used to decide whether a quarantined node has recovered.
+func ProbeNode(ctx context.Context, client *http.Client, scheme, node string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+node+"/localnodes", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("alternator: probe of node %s returned status %d", node, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package shared
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectPolicyBackoff(t *testing.T) {
+	policy := ReconnectPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: 100 * time.Millisecond},
+		{attempt: 1, want: 200 * time.Millisecond},
+		{attempt: 2, want: 400 * time.Millisecond},
+		{attempt: 3, want: 800 * time.Millisecond},
+		{attempt: 4, want: time.Second}, // capped by MaxBackoff
+	}
+
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestReconnectPolicyBackoffJitter(t *testing.T) {
+	policy := ReconnectPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+		Jitter:         true,
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		d := policy.Backoff(attempt)
+		if d < 0 || d > time.Second {
+			t.Errorf("Backoff(%d) = %v, want within [0, 1s]", attempt, d)
+		}
+	}
+}
+
+func TestNodeHealthQuarantineAndRecovery(t *testing.T) {
+	var transitions []NodeState
+	policy := ReconnectPolicy{
+		MaxConsecutiveFailures: 3,
+		OnNodeStateChange: func(_ string, _, newState NodeState) {
+			transitions = append(transitions, newState)
+		},
+	}
+
+	h := NewNodeHealth("node-0", policy)
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		h.RecordFailure(now)
+	}
+	if h.State() != NodeStateHealthy {
+		t.Fatalf("expected node to stay healthy below MaxConsecutiveFailures, got %s", h.State())
+	}
+
+	h.RecordFailure(now)
+	if h.State() != NodeStateQuarantined {
+		t.Fatalf("expected node to be quarantined after %d consecutive failures, got %s", h.ConsecutiveFailures(), h.State())
+	}
+
+	h.RecordSuccess()
+	if h.State() != NodeStateHealthy {
+		t.Fatalf("expected node to recover after a success, got %s", h.State())
+	}
+
+	want := []NodeState{NodeStateQuarantined, NodeStateHealthy}
+	if len(transitions) != len(want) || transitions[0] != want[0] || transitions[1] != want[1] {
+		t.Errorf("OnNodeStateChange transitions = %v, want %v", transitions, want)
+	}
+}
+
+func TestNodeHealthFailureDecay(t *testing.T) {
+	policy := ReconnectPolicy{
+		MaxBackoff:             time.Minute,
+		MaxConsecutiveFailures: 2,
+	}
+	h := NewNodeHealth("node-0", policy)
+
+	start := time.Now()
+	h.RecordFailure(start)
+
+	// A failure well past the decay window resets the streak instead of
+	// compounding with the earlier one.
+	h.RecordFailure(start.Add(2 * time.Minute))
+	if got := h.ConsecutiveFailures(); got != 1 {
+		t.Errorf("ConsecutiveFailures() = %d, want 1 after the decay window elapsed", got)
+	}
+	if h.State() != NodeStateHealthy {
+		t.Errorf("expected node to stay healthy, got %s", h.State())
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"net/http"
+
+	"github.com/scylladb/alternator-client-golang/shared/metrics"
 )
 
 // DefaultHTTPTransport creates default `http.Transport`
@@ -18,17 +20,42 @@ func DefaultHTTPTransport() *http.Transport {
 func NewALNHTTPTransport(config ALNConfig) http.RoundTripper {
 	transport := DefaultHTTPTransport()
 	PatchHTTPTransport(config, transport)
+
+	roundTripper, err := configureHTTP2(config, transport)
+	if err != nil {
+		if config.Logger != nil {
+			config.Logger.Printf("alternator: failed to configure HTTP/2, falling back to HTTP/1.1: %v", err)
+		}
+		roundTripper = transport
+	}
+
+	if collectors := config.Metrics; collectors != nil || config.MetricsRegisterer != nil {
+		if collectors == nil {
+			collectors = metrics.New(config.MetricsRegisterer)
+		}
+		roundTripper = metrics.InstrumentRoundTripper(roundTripper, collectors)
+		roundTripper = metrics.InstrumentRequestRoundTripper(roundTripper, collectors)
+	}
+
 	if config.HTTPTransportWrapper != nil {
-		return config.HTTPTransportWrapper(transport)
+		return config.HTTPTransportWrapper(roundTripper)
 	}
-	return transport
+	return roundTripper
 }
 
 // PatchHTTPTransport patches `http.Transport` based on provided `ALNConfig`
 func PatchHTTPTransport(config ALNConfig, transport *http.Transport) http.RoundTripper {
 	transport.IdleConnTimeout = config.IdleHTTPConnectionTimeout
 	transport.MaxIdleConns = config.MaxIdleHTTPConnections
-	transport.MaxIdleConnsPerHost = config.MaxIdleHTTPConnections
+	transport.MaxIdleConnsPerHost = config.MaxIdleHTTPConnectionsPerHost
+	// transport still owns the dial and connection pool for TLS-negotiated
+	// HTTP/2 (ALPN picks h2 after transport's own handshake), so
+	// MaxConnsPerHost is honored there. It has no effect on cleartext h2c
+	// traffic, which bypasses transport entirely in favor of dialing
+	// directly through the *http2.Transport configured by configureHTTP2;
+	// that path enforces its own per-host cap instead, via
+	// perHostDialLimiter.
+	transport.MaxConnsPerHost = config.MaxHTTPConnectionsPerHost
 
 	if transport.TLSClientConfig == nil {
 		transport.TLSClientConfig = &tls.Config{}
@@ -45,8 +72,12 @@ func PatchHTTPTransport(config ALNConfig, transport *http.Transport) http.RoundT
 		}
 	}
 
-	if config.TLSSessionCache != nil {
-		transport.TLSClientConfig.ClientSessionCache = config.TLSSessionCache
+	sessionCache := config.TLSSessionCache
+	if sessionCache == nil && config.TLSSessionCacheCapacity > 0 {
+		sessionCache = tls.NewLRUClientSessionCache(config.TLSSessionCacheCapacity)
+	}
+	if sessionCache != nil {
+		transport.TLSClientConfig.ClientSessionCache = sessionCache
 	}
 
 	if config.ClientCertificateSource != nil {